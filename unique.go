@@ -11,15 +11,28 @@ a time order
 
 The format of the timestamps is as follows.
 
-0000543cef9f-0000b9d-c42c0319bdbe
+0000543cef9f-0000b9d1-c42c0319bdbe
 
 The first part is the timestamp in hex.  The high-order two bytes of the 64-but timestamp are not included.
 The second part is a randomly initialised monotonically increasing sequence number.  The final part is the
 machine mac address (or a random value if this is not available.)
 
-Note if you use the package in multiple processes in the same machine it is not guaranteed unique.  Perhaps
-I should use this as a hint to use a random value for the 3rd part instead of the MAC addresss.  This may
-change soon...
+BREAKING CHANGE: versions of this package before the Generator was introduced had an off-by-one in
+String() that placed the second separator one byte early, truncating the sequence number to 7 hex digits
+and leaving the last byte of the hw-addr part always zero.  That's now fixed, so a String() produced by
+this version sorts and parses differently to one produced by an older version.  If you've persisted these
+strings as sort keys in an external store, don't mix old and new rows without migrating them first.
+
+Note if you use the package in multiple processes in the same machine it is not guaranteed unique with the
+default MACNode strategy, since every process on the machine shares the same MAC address.  Construct a
+Generator with NewGeneratorWithNode and a different NodeID implementation, such as
+HashedHostnamePIDNode, to distinguish processes on the same host.
+
+NewUniqueTimestamp (and UniqueTimestamp.Timestamp generally) always honours the timestamp it's given, even
+an earlier one than has already been used - it's meant for explicit timestamps, including historical ones,
+not just the current time.  Generator.New(), which is driven by the wall clock, is the one place that
+guards against the clock going backwards or repeating: it reuses the previous timestamp and bumps the
+sequence number instead, so its String() form stays strictly increasing even across a clock adjustment.
 */
 package go_unique_ts
 
@@ -27,45 +40,101 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
-	"net"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
+	"time"
 )
 
-var (
-	hwAddr [6]byte
-	seqNo  uint32
-)
+func newSeqNo() uint32 {
+	var clockSeqRand [2]byte
+	io.ReadFull(rand.Reader, clockSeqRand[:])
+	return uint32(clockSeqRand[1])<<8 | uint32(clockSeqRand[0])
+}
 
-func init() {
-	hwAddrSet := false
-	if interfaces, err := net.Interfaces(); err == nil {
-		for _, i := range interfaces {
-			if i.Flags&net.FlagLoopback == 0 && len(i.HardwareAddr) > 0 {
-				copy(hwAddr[:], i.HardwareAddr)
-				hwAddrSet = true
-				break
-			}
-		}
+// Generator produces UniqueTimestamps from a shared hardware address and sequence number.  NewAt always
+// honours the timestamp it's given, so it's safe to use for historical or backdated values; New(), which
+// is driven by the wall clock rather than a caller-supplied value, additionally guards against the clock
+// going backwards or repeating by reusing the last timestamp it saw and bumping the sequence number, so
+// its String() form stays strictly increasing even across a clock adjustment.
+type Generator struct {
+	mu            sync.Mutex
+	lastTimestamp int64
+	seqNo         uint32
+	hwAddr        [6]byte
+
+	// State for the V7 format, see unique_v7.go.
+	lastMs    int64
+	v7Counter uint8
+}
+
+// nowMs returns the current time as milliseconds since 1 Jan 1970.
+func nowMs() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
+// NewGenerator creates a Generator seeded with a random initial sequence number and the node ID
+// produced by MACNode, matching the seeding the package-level functions used to do in init().  Use
+// NewGeneratorWithNode to choose a different NodeID strategy.
+func NewGenerator() *Generator {
+	return NewGeneratorWithNode(MACNode{})
+}
+
+// NewGeneratorWithNode creates a Generator seeded with a random initial sequence number and the node ID
+// produced by the given NodeID strategy.
+func NewGeneratorWithNode(node NodeID) *Generator {
+	return &Generator{
+		seqNo:  newSeqNo(),
+		hwAddr: node.NodeID(),
 	}
-	if !hwAddrSet {
-		// If we failed to obtain the MAC address of the current computer,
-		// we will use a randomly generated 6 byte sequence instead and set
-		// the multicast bit as recommended in RFC 4122.
-		_, err := io.ReadFull(rand.Reader, hwAddr[:])
-		if err != nil {
-			panic(err)
-		}
-		hwAddr[0] = hwAddr[0] | 0x01
+}
+
+// New returns a new UniqueTimestamp for the current time, as returned by time.Now().  If the wall clock
+// has not advanced past the last time New() saw - it went backwards, or this is being called more than
+// once a second - the clock sequence is bumped and the previous timestamp is reused instead, so the
+// resulting String() is still strictly greater than the one before it.
+func (g *Generator) New() UniqueTimestamp {
+	return g.newGuarded(time.Now().Unix())
+}
+
+// newGuarded implements the wall-clock regression guard described on New(), taking the current time as
+// a parameter so it can be exercised with fake clock values in tests.
+func (g *Generator) newGuarded(now int64) UniqueTimestamp {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now <= g.lastTimestamp {
+		now = g.lastTimestamp
+	} else {
+		g.lastTimestamp = now
 	}
+	g.seqNo++
 
-	// initialize the clock sequence with a random number
-	var clockSeqRand [2]byte
-	io.ReadFull(rand.Reader, clockSeqRand[:])
-	seqNo = uint32(clockSeqRand[1])<<8 | uint32(clockSeqRand[0])
+	return UniqueTimestamp{
+		Timestamp: now,
+		seqNo:     g.seqNo,
+		hwAddr:    g.hwAddr,
+	}
 }
 
+// NewAt returns a new UniqueTimestamp for the given timestamp, unconditionally.  Unlike New(), it never
+// rewrites timestamp, even if it's earlier than one already produced by this Generator - it's meant for
+// explicit, possibly historical, timestamps rather than the current wall clock.
+func (g *Generator) NewAt(timestamp int64) UniqueTimestamp {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.seqNo++
+
+	return UniqueTimestamp{
+		Timestamp: timestamp,
+		seqNo:     g.seqNo,
+		hwAddr:    g.hwAddr,
+	}
+}
+
+var defaultGenerator = NewGenerator()
+
 type UniqueTimestamp struct {
 	// Timestamp - expected to be in seconds since 1 Jan 1970.  high-order 2 bytes are ignored.
 	Timestamp int64
@@ -77,11 +146,7 @@ type UniqueTimestamp struct {
 Create a new UniqueTimestamp for a given timestamp
 */
 func NewUniqueTimestamp(timestamp int64) UniqueTimestamp {
-	return UniqueTimestamp{
-		Timestamp: timestamp,
-		seqNo:     atomic.AddUint32(&seqNo, 1),
-		hwAddr:    hwAddr,
-	}
+	return defaultGenerator.NewAt(timestamp)
 }
 
 /*
@@ -125,12 +190,19 @@ func (u *UniqueTimestamp) FromString(val string) error {
 	}
 	u.Timestamp = timestamp
 
+	if len(parts[1]) != 8 {
+		return fmt.Errorf("seqno part should be 8 hex characters, got %d", len(parts[1]))
+	}
 	seqNo, err := strconv.ParseUint(parts[1], 16, 32)
 	if err != nil {
 		return fmt.Errorf("could not parse seqno part: %v", err)
 	}
 	u.seqNo = uint32(seqNo)
 
+	if len(parts[2]) != 12 {
+		return fmt.Errorf("hw addr part should be 12 hex characters, got %d", len(parts[2]))
+	}
+
 	for i := range u.hwAddr {
 		b, err := strconv.ParseUint(parts[2][2*i:2*i+2], 16, 8)
 		if err != nil {
@@ -144,6 +216,18 @@ func (u *UniqueTimestamp) FromString(val string) error {
 
 const hexString = "0123456789abcdef"
 
+// encodeTimestampHex returns the 12 character hex encoding of the 48-bit seconds timestamp used by
+// UniqueTimestamp, shared by String() and the prefix helpers in unique_range.go.
+func encodeTimestampHex(ts int64) string {
+	r := make([]byte, 12)
+	for i := 0; i < 6; i++ {
+		b := (ts >> uint(40-(i*8))) & 0xFF
+		r[2*i] = hexString[b>>4]
+		r[2*i+1] = hexString[b&0xF]
+	}
+	return string(r)
+}
+
 /*
 Get the string representation of a UniqueTimestamp.
 */
@@ -152,21 +236,17 @@ func (u UniqueTimestamp) String() string {
 	// Can ignore top 2 bytes of TS for a few hundred years
 	// 12 - 8 - 12
 	r := make([]byte, 34)
-	for i := 0; i < 6; i++ {
-		b := (u.Timestamp >> uint(40-(i*8))) & 0xFF
-		r[2*i] = hexString[b>>4]
-		r[2*i+1] = hexString[b&0xF]
-	}
+	copy(r, encodeTimestampHex(u.Timestamp))
 	r[12] = '-'
 	for i := 0; i < 4; i++ {
 		b := (u.seqNo >> uint(24-(i*8))) & 0xFF
 		r[13+2*i] = hexString[b>>4]
 		r[14+2*i] = hexString[b&0xF]
 	}
-	r[20] = '-'
+	r[21] = '-'
 	for i, b := range u.hwAddr {
-		r[21+2*i] = hexString[b>>4]
-		r[22+2*i] = hexString[b&0xF]
+		r[22+2*i] = hexString[b>>4]
+		r[23+2*i] = hexString[b&0xF]
 	}
 
 	return string(r)
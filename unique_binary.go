@@ -0,0 +1,114 @@
+package go_unique_ts
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// binarySize is the length in bytes of the binary encoding of a UniqueTimestamp: 6 bytes timestamp,
+// 4 bytes sequence number, 6 bytes hardware address.
+const binarySize = 16
+
+// MarshalBinary encodes a UniqueTimestamp as 16 bytes: 6 bytes timestamp, 4 bytes sequence number, 6 bytes
+// hardware address, all big-endian.  This preserves the same ordering as String(), so the result can be used
+// directly as a sort key in byte-oriented stores such as BoltDB or Badger.
+func (u UniqueTimestamp) MarshalBinary() ([]byte, error) {
+	r := make([]byte, binarySize)
+	for i := 0; i < 6; i++ {
+		r[i] = byte(u.Timestamp >> uint(40-(i*8)))
+	}
+	for i := 0; i < 4; i++ {
+		r[6+i] = byte(u.seqNo >> uint(24-(i*8)))
+	}
+	copy(r[10:], u.hwAddr[:])
+
+	return r, nil
+}
+
+// UnmarshalBinary decodes a UniqueTimestamp from the 16 byte form produced by MarshalBinary.
+func (u *UniqueTimestamp) UnmarshalBinary(data []byte) error {
+	if len(data) != binarySize {
+		return fmt.Errorf("unique timestamp binary form should be %d bytes, got %d", binarySize, len(data))
+	}
+
+	var timestamp int64
+	for i := 0; i < 6; i++ {
+		timestamp = timestamp<<8 | int64(data[i])
+	}
+	u.Timestamp = timestamp
+
+	var seqNo uint32
+	for i := 0; i < 4; i++ {
+		seqNo = seqNo<<8 | uint32(data[6+i])
+	}
+	u.seqNo = seqNo
+
+	copy(u.hwAddr[:], data[10:])
+
+	return nil
+}
+
+// MarshalText encodes a UniqueTimestamp as its String() form.
+func (u UniqueTimestamp) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText decodes a UniqueTimestamp from its String() form.
+func (u *UniqueTimestamp) UnmarshalText(text []byte) error {
+	return u.FromString(string(text))
+}
+
+// MarshalJSON encodes a UniqueTimestamp as a JSON string, using its String() form.
+func (u UniqueTimestamp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+// UnmarshalJSON decodes a UniqueTimestamp from a JSON string in its String() form.
+func (u *UniqueTimestamp) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	return u.FromString(s)
+}
+
+// Value implements driver.Valuer so a UniqueTimestamp can be used directly as a column value, stored as its
+// 16 byte binary form.
+func (u UniqueTimestamp) Value() (driver.Value, error) {
+	b, _ := u.MarshalBinary()
+	return b, nil
+}
+
+// Scan implements sql.Scanner, accepting either the 16 byte binary form or the String() form.
+func (u *UniqueTimestamp) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case []byte:
+		if len(v) == binarySize {
+			return u.UnmarshalBinary(v)
+		}
+		return u.FromString(string(v))
+	case string:
+		return u.FromString(v)
+	case nil:
+		return fmt.Errorf("cannot scan nil into UniqueTimestamp")
+	default:
+		return fmt.Errorf("cannot scan %T into UniqueTimestamp", src)
+	}
+}
+
+// Compare returns -1, 0 or 1 depending on whether a is less than, equal to, or greater than b, ordering
+// the same way their String() forms do.
+func Compare(a, b UniqueTimestamp) int {
+	ab, _ := a.MarshalBinary()
+	bb, _ := b.MarshalBinary()
+	return bytes.Compare(ab, bb)
+}
+
+// Less reports whether a sorts before b.  It's intended for use as the comparison function passed to
+// sort.Slice.
+func Less(a, b UniqueTimestamp) bool {
+	return Compare(a, b) < 0
+}
@@ -0,0 +1,108 @@
+package go_unique_ts
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestBinaryRoundTrip(t *testing.T) {
+	ts := time.Now().Unix()
+	t1 := NewUniqueTimestamp(ts)
+
+	b, err := t1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if len(b) != binarySize {
+		t.Fatalf("expected %d bytes, got %d", binarySize, len(b))
+	}
+
+	var t2 UniqueTimestamp
+	if err := t2.UnmarshalBinary(b); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if t2 != t1 {
+		t.Fatalf("round trip through binary did not preserve the value: %s != %s", t2, t1)
+	}
+}
+
+func TestBinaryOrderMatchesString(t *testing.T) {
+	ts := time.Now().Unix()
+	g := NewGenerator()
+	t1 := g.NewAt(ts)
+	t2 := g.NewAt(ts + 1)
+
+	b1, _ := t1.MarshalBinary()
+	b2, _ := t2.MarshalBinary()
+
+	if Compare(t1, t2) >= 0 {
+		t.Fatalf("Compare should report t1 < t2")
+	}
+
+	if (string(b1) < string(b2)) != (t1.String() < t2.String()) {
+		t.Fatalf("binary order does not match string order: b1=%x b2=%x, t1=%s t2=%s", b1, b2, t1, t2)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	ts := time.Now().Unix()
+	t1 := NewUniqueTimestamp(ts)
+
+	data, err := json.Marshal(t1)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+
+	var t2 UniqueTimestamp
+	if err := json.Unmarshal(data, &t2); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if t2 != t1 {
+		t.Fatalf("round trip through JSON did not preserve the value: %s != %s", t2, t1)
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	ts := time.Now().Unix()
+	t1 := NewUniqueTimestamp(ts)
+
+	v, err := t1.Value()
+	if err != nil {
+		t.Fatalf("failed to get value: %v", err)
+	}
+
+	var t2 UniqueTimestamp
+	if err := t2.Scan(v); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+
+	if t2 != t1 {
+		t.Fatalf("round trip through Value/Scan did not preserve the value: %s != %s", t2, t1)
+	}
+}
+
+func TestScanMalformed(t *testing.T) {
+	var u UniqueTimestamp
+
+	if err := u.Scan("0000543cef9f-0000b9d1-c42c03"); err == nil {
+		t.Fatalf("expected an error scanning a truncated string, got none")
+	}
+}
+
+func TestSortSlice(t *testing.T) {
+	ts := time.Now().Unix()
+	g := NewGenerator()
+	ids := []UniqueTimestamp{g.NewAt(ts + 2), g.NewAt(ts), g.NewAt(ts + 1)}
+
+	sort.Slice(ids, func(i, j int) bool { return Less(ids[i], ids[j]) })
+
+	for i := 1; i < len(ids); i++ {
+		if !Less(ids[i-1], ids[i]) {
+			t.Fatalf("slice not sorted at index %d: %s >= %s", i, ids[i-1], ids[i])
+		}
+	}
+}
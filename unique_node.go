@@ -0,0 +1,93 @@
+package go_unique_ts
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NodeID supplies the 6 bytes that identify the machine or process a Generator is running on.  It's
+// the pluggable replacement for the MAC address that used to be picked once in init() and shared by
+// every Generator in the process.
+type NodeID interface {
+	NodeID() [6]byte
+}
+
+// MACNode identifies a Generator by this machine's MAC address, falling back to a random value with
+// the multicast bit set (as recommended by RFC 4122) if no address is available.  It's the default
+// strategy, kept for backward compatibility with earlier versions of this package.
+type MACNode struct{}
+
+func (MACNode) NodeID() [6]byte {
+	var id [6]byte
+	if interfaces, err := net.Interfaces(); err == nil {
+		for _, i := range interfaces {
+			if i.Flags&net.FlagLoopback == 0 && len(i.HardwareAddr) > 0 {
+				copy(id[:], i.HardwareAddr)
+				return id
+			}
+		}
+	}
+
+	return RandomNode{}.NodeID()
+}
+
+// RandomNode identifies a Generator by a freshly generated random value, with the multicast bit set as
+// recommended by RFC 4122 to mark it as not being a real MAC address.
+type RandomNode struct{}
+
+func (RandomNode) NodeID() [6]byte {
+	var id [6]byte
+	if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+		panic(err)
+	}
+	id[0] = id[0] | 0x01
+
+	return id
+}
+
+// startupNanos is captured once, the first time it's needed, so that every HashedHostnamePIDNode in this
+// process hashes to the same node ID.
+var startupNanos = sync.OnceValue(func() int64 {
+	return time.Now().UnixNano()
+})
+
+// HashedHostnamePIDNode identifies a Generator by hashing this machine's hostname together with the
+// process ID and the time this process started, truncated to 6 bytes with the multicast bit set.
+// Unlike MACNode, this distinguishes different processes running on the same machine, addressing the
+// multi-process uniqueness caveat in the package doc comment.  Unlike RandomNode, every
+// HashedHostnamePIDNode constructed in the same process resolves to the same node ID.
+type HashedHostnamePIDNode struct{}
+
+func (HashedHostnamePIDNode) NodeID() [6]byte {
+	hostname, _ := os.Hostname()
+	return hashHostnamePID(hostname, os.Getpid(), startupNanos())
+}
+
+// hashHostnamePID does the actual hashing for HashedHostnamePIDNode.NodeID, taking the hostname, pid
+// and startup time as parameters so tests can check that distinct triples hash to distinct node IDs
+// without having to spawn separate processes.
+func hashHostnamePID(hostname string, pid int, startup int64) [6]byte {
+	data := hostname + "-" + strconv.Itoa(pid) + "-" + strconv.FormatInt(startup, 10)
+
+	sum := sha256.Sum256([]byte(data))
+
+	var id [6]byte
+	copy(id[:], sum[:6])
+	id[0] = id[0] | 0x01
+
+	return id
+}
+
+// StaticNode identifies a Generator by a fixed, caller-supplied value, e.g. one derived from a
+// Kubernetes pod name or a statically assigned shard number.
+type StaticNode [6]byte
+
+func (s StaticNode) NodeID() [6]byte {
+	return s
+}
@@ -0,0 +1,58 @@
+package go_unique_ts
+
+import "testing"
+
+func TestStaticNode(t *testing.T) {
+	id := StaticNode{1, 2, 3, 4, 5, 6}
+
+	if id.NodeID() != [6]byte{1, 2, 3, 4, 5, 6} {
+		t.Fatalf("StaticNode should return the value it was given, got %v", id.NodeID())
+	}
+}
+
+func TestRandomNodeMulticastBit(t *testing.T) {
+	id := RandomNode{}.NodeID()
+
+	if id[0]&0x01 == 0 {
+		t.Fatalf("RandomNode should set the multicast bit, got %08b", id[0])
+	}
+}
+
+func TestHashedHostnamePIDNodeMulticastBit(t *testing.T) {
+	a := HashedHostnamePIDNode{}.NodeID()
+
+	if a[0]&0x01 == 0 {
+		t.Fatalf("HashedHostnamePIDNode should set the multicast bit, got %08b", a[0])
+	}
+}
+
+func TestHashedHostnamePIDNodeDistinguishesProcesses(t *testing.T) {
+	a := hashHostnamePID("host-a", 100, 1000)
+	b := hashHostnamePID("host-b", 100, 1000)
+	c := hashHostnamePID("host-a", 200, 1000)
+	d := hashHostnamePID("host-a", 100, 2000)
+
+	if a == b || a == c || a == d {
+		t.Fatalf("different hostname/pid/startup triples should hash to different node IDs, got a=%v b=%v c=%v d=%v", a, b, c, d)
+	}
+}
+
+func TestHashedHostnamePIDNodeStableWithinProcess(t *testing.T) {
+	a := HashedHostnamePIDNode{}.NodeID()
+	b := HashedHostnamePIDNode{}.NodeID()
+
+	if a != b {
+		t.Fatalf("two HashedHostnamePIDNodes in the same process should produce the same ID, got %v and %v", a, b)
+	}
+}
+
+func TestGeneratorWithNode(t *testing.T) {
+	node := StaticNode{0xAA, 0xBB, 0xCC, 0xDD, 0xEE, 0xFF}
+	g := NewGeneratorWithNode(node)
+
+	ts := g.New()
+
+	if ts.hwAddr != [6]byte(node) {
+		t.Fatalf("generator should have used the node's ID, got %v", ts.hwAddr)
+	}
+}
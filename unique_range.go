@@ -0,0 +1,65 @@
+package go_unique_ts
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/*
+Range returns the (minString, maxString) pair of String() forms bounding every UniqueTimestamp with a
+timestamp between from and to inclusive.  The pair is suitable for a prefix or range scan in a
+RocksDB/Badger/BoltDB style store: iterate all keys k with minString <= k <= maxString.
+*/
+func Range(from, to int64) (string, string) {
+	return MinUniqueTimestamp(from).String(), MaxUniqueTimestamp(to).String()
+}
+
+/*
+PrefixForSecond returns the 12 character hex prefix common to the String() form of every
+UniqueTimestamp for the given second.
+*/
+func PrefixForSecond(ts int64) string {
+	return encodeTimestampHex(ts)
+}
+
+/*
+PrefixForMinute returns the 12 character hex encoding of the start of the minute containing ts.  Unlike
+PrefixForSecond this isn't a common prefix of every UniqueTimestamp in the minute - a calendar minute
+doesn't line up with a hex digit boundary - so pair it with Range(minuteStart, minuteStart+59) to get a
+proper scan bound.
+*/
+func PrefixForMinute(ts int64) string {
+	return encodeTimestampHex(floorToBucket(ts, 60))
+}
+
+/*
+PrefixForHour returns the 12 character hex encoding of the start of the hour containing ts.  As with
+PrefixForMinute, this is the start of the bucket rather than a guaranteed common prefix; pair it with
+Range(hourStart, hourStart+3599) for a proper scan bound.
+*/
+func PrefixForHour(ts int64) string {
+	return encodeTimestampHex(floorToBucket(ts, 3600))
+}
+
+// floorToBucket rounds ts down to the nearest multiple of size, unlike Go's %, which truncates toward
+// zero and so rounds negative (pre-1970) timestamps up rather than down.
+func floorToBucket(ts, size int64) int64 {
+	m := ts % size
+	if m < 0 {
+		m += size
+	}
+	return ts - m
+}
+
+/*
+TimestampFromString parses just the leading 12 hex characters of a UniqueTimestamp's String() form,
+without validating or allocating for the rest of it.  This is a fast path for scanning large ranges of
+keys and filtering by timestamp without fully deserializing each one.
+*/
+func TimestampFromString(s string) (int64, error) {
+	if len(s) < 12 {
+		return 0, fmt.Errorf("timestamp string too short to contain a timestamp part: %q", s)
+	}
+
+	return strconv.ParseInt(s[:12], 16, 64)
+}
@@ -0,0 +1,81 @@
+package go_unique_ts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	ts := time.Now().Unix()
+
+	min, max := Range(ts, ts+10)
+
+	g := NewGenerator()
+	inside := g.NewAt(ts + 5)
+
+	if inside.String() < min || inside.String() > max {
+		t.Fatalf("timestamp inside the range fell outside it: %s not in [%s, %s]", inside, min, max)
+	}
+
+	if MinUniqueTimestamp(ts-1).String() >= min {
+		t.Fatalf("timestamp before the range should sort before min")
+	}
+
+	if MaxUniqueTimestamp(ts+11).String() <= max {
+		t.Fatalf("timestamp after the range should sort after max")
+	}
+}
+
+func TestPrefixForSecond(t *testing.T) {
+	ts := time.Now().Unix()
+
+	prefix := PrefixForSecond(ts)
+	full := NewUniqueTimestamp(ts).String()
+
+	if full[:12] != prefix {
+		t.Fatalf("PrefixForSecond %q did not match String() prefix %q", prefix, full[:12])
+	}
+}
+
+func TestPrefixForMinuteAndHour(t *testing.T) {
+	ts := time.Now().Unix()
+
+	if PrefixForMinute(ts) != encodeTimestampHex(floorToBucket(ts, 60)) {
+		t.Fatalf("PrefixForMinute did not encode the start of the minute")
+	}
+
+	if PrefixForHour(ts) != encodeTimestampHex(floorToBucket(ts, 3600)) {
+		t.Fatalf("PrefixForHour did not encode the start of the hour")
+	}
+}
+
+func TestPrefixForMinuteAndHourBeforeEpoch(t *testing.T) {
+	// The last second of the minute/hour before the epoch.
+	ts := int64(-1)
+
+	if PrefixForMinute(ts) != encodeTimestampHex(-60) {
+		t.Fatalf("PrefixForMinute should round a negative timestamp down to the start of its minute")
+	}
+
+	if PrefixForHour(ts) != encodeTimestampHex(-3600) {
+		t.Fatalf("PrefixForHour should round a negative timestamp down to the start of its hour")
+	}
+}
+
+func TestTimestampFromString(t *testing.T) {
+	ts := time.Now().Unix()
+	t1 := NewUniqueTimestamp(ts)
+
+	parsed, err := TimestampFromString(t1.String())
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if parsed != t1.Timestamp {
+		t.Fatalf("TimestampFromString returned %d, want %d", parsed, t1.Timestamp)
+	}
+
+	if _, err := TimestampFromString("short"); err == nil {
+		t.Fatalf("expected an error for a too-short string")
+	}
+}
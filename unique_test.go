@@ -33,6 +33,68 @@ func TestOrder(t *testing.T) {
 
 }
 
+func TestGeneratorClockRegression(t *testing.T) {
+	ts := time.Now().Unix()
+
+	g := NewGenerator()
+	t1 := g.newGuarded(ts)
+	t2 := g.newGuarded(ts - 10)
+	t3 := g.newGuarded(ts - 5)
+
+	if t2.String() <= t1.String() {
+		t.Fatalf("timestamp regression was not guarded against: t1=%s, t2=%s", t1, t2)
+	}
+
+	if t3.String() <= t2.String() {
+		t.Fatalf("timestamp regression was not guarded against: t2=%s, t3=%s", t2, t3)
+	}
+
+	if t2.Timestamp != t1.Timestamp || t3.Timestamp != t1.Timestamp {
+		t.Fatalf("generator should have clamped regressed timestamps to %d, got t2=%d, t3=%d", t1.Timestamp, t2.Timestamp, t3.Timestamp)
+	}
+}
+
+func TestGeneratorRepeatedTimestamp(t *testing.T) {
+	ts := time.Now().Unix()
+
+	g := NewGenerator()
+	t1 := g.newGuarded(ts)
+	t2 := g.newGuarded(ts)
+
+	if t2.String() <= t1.String() {
+		t.Fatalf("repeated timestamp did not produce an increasing string: t1=%s, t2=%s", t1, t2)
+	}
+}
+
+// NewAt, unlike New()/newGuarded(), must never rewrite the timestamp it's given - it's the path
+// NewUniqueTimestamp uses, and callers backfilling historical data rely on getting back exactly the
+// timestamp they asked for regardless of what this Generator generated before.
+func TestNewAtHonoursExplicitTimestamp(t *testing.T) {
+	g := NewGenerator()
+
+	t1 := g.NewAt(1000)
+	_ = g.NewAt(1100)
+	t2 := g.NewAt(1000)
+
+	if t1.Timestamp != 1000 {
+		t.Fatalf("expected t1.Timestamp == 1000, got %d", t1.Timestamp)
+	}
+
+	if t2.Timestamp != 1000 {
+		t.Fatalf("NewAt should not clamp an explicit timestamp to a later one this Generator already produced, got %d", t2.Timestamp)
+	}
+}
+
+func TestNewUniqueTimestampHonoursExplicitTimestamp(t *testing.T) {
+	t1 := NewUniqueTimestamp(1000)
+	_ = NewUniqueTimestamp(1100)
+	t2 := NewUniqueTimestamp(1000)
+
+	if t1.Timestamp != 1000 || t2.Timestamp != 1000 {
+		t.Fatalf("NewUniqueTimestamp should always honour its explicit timestamp argument, got t1=%d, t2=%d", t1.Timestamp, t2.Timestamp)
+	}
+}
+
 func TestParse(t *testing.T) {
 	ts := time.Now().Unix()
 
@@ -50,3 +112,18 @@ func TestParse(t *testing.T) {
 		t.Fatalf("timestamp not preserved by parsing")
 	}
 }
+
+func TestParseMalformed(t *testing.T) {
+	var u UniqueTimestamp
+
+	for _, val := range []string{
+		"",
+		"0000543cef9f",
+		"0000543cef9f-0000b9d1-c42c03",
+		"0000543cef9f-0000b9d1-c42c0319bdbe00",
+	} {
+		if err := u.FromString(val); err == nil {
+			t.Fatalf("expected an error parsing %q, got none", val)
+		}
+	}
+}
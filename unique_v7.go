@@ -0,0 +1,135 @@
+package go_unique_ts
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/*
+UniqueTimestampV7 is a millisecond-resolution alternative to UniqueTimestamp, modelled on the UUIDv7
+draft.  Where UniqueTimestamp relies on the machine's MAC address to avoid collisions, UniqueTimestampV7
+fills the non-timestamp bytes with cryptographically random data, so it is safe to use from multiple
+processes on the same machine without any node identifier at all.
+
+The format of the string form is as follows.
+
+0189dae16a4f-00-c42c0319bdbe129a7f
+
+The first part is the 48-bit millisecond Unix timestamp in hex.  The second part is a counter that is
+bumped whenever more than one UniqueTimestampV7 is generated within the same millisecond, so that IDs
+generated in quick succession still sort after one another.  The final part is 9 bytes of random data,
+which makes collisions between concurrent generators vanishingly unlikely.
+*/
+type UniqueTimestampV7 struct {
+	// TimestampMs - milliseconds since 1 Jan 1970.  high-order 2 bytes are ignored.
+	TimestampMs int64
+	counter     uint8
+	rand        [9]byte
+}
+
+// NewV7 returns a new UniqueTimestampV7 for the current time, as returned by time.Now().
+func (g *Generator) NewV7() UniqueTimestampV7 {
+	return g.NewAtV7(nowMs())
+}
+
+// NewAtV7 returns a new UniqueTimestampV7 for the given millisecond timestamp.  If ms has not advanced
+// past the last one this Generator saw, the counter is bumped and the previous millisecond is reused so
+// that the resulting String() is still strictly greater than the one before it.  If the counter itself
+// overflows - more than 256 UniqueTimestampV7s generated in the same millisecond - ms is rolled forward
+// by one to make room for it.
+func (g *Generator) NewAtV7(ms int64) UniqueTimestampV7 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.v7Counter++
+		if g.v7Counter == 0 {
+			ms++
+			g.lastMs = ms
+		}
+	} else {
+		g.lastMs = ms
+		g.v7Counter = 0
+	}
+
+	var rnd [9]byte
+	if _, err := io.ReadFull(rand.Reader, rnd[:]); err != nil {
+		panic(err)
+	}
+
+	return UniqueTimestampV7{
+		TimestampMs: ms,
+		counter:     g.v7Counter,
+		rand:        rnd,
+	}
+}
+
+/*
+Create a new UniqueTimestampV7 for the current time.
+*/
+func NewUniqueTimestampV7() UniqueTimestampV7 {
+	return defaultGenerator.NewV7()
+}
+
+/*
+Get the string representation of a UniqueTimestampV7.
+*/
+func (u UniqueTimestampV7) String() string {
+	// Format is timestamp bytes - counter byte - random bytes
+	// 12 - 2 - 18
+	r := make([]byte, 34)
+	copy(r, encodeTimestampHex(u.TimestampMs))
+	r[12] = '-'
+	r[13] = hexString[u.counter>>4]
+	r[14] = hexString[u.counter&0xF]
+	r[15] = '-'
+	for i, b := range u.rand {
+		r[16+2*i] = hexString[b>>4]
+		r[17+2*i] = hexString[b&0xF]
+	}
+
+	return string(r)
+}
+
+/*
+Parse a UniqueTimestampV7.
+*/
+func (u *UniqueTimestampV7) FromString(val string) error {
+	parts := strings.Split(val, "-")
+	if len(parts) != 3 {
+		return fmt.Errorf("timestamp should contain 2 -")
+	}
+
+	ms, err := strconv.ParseInt(parts[0], 16, 64)
+	if err != nil {
+		return fmt.Errorf("could not parse timestamp part: %v", err)
+	}
+	u.TimestampMs = ms
+
+	if len(parts[1]) != 2 {
+		return fmt.Errorf("counter part should be 2 hex characters, got %d", len(parts[1]))
+	}
+	counter, err := strconv.ParseUint(parts[1], 16, 8)
+	if err != nil {
+		return fmt.Errorf("could not parse counter part: %v", err)
+	}
+	u.counter = uint8(counter)
+
+	if len(parts[2]) != 18 {
+		return fmt.Errorf("random part should be 18 hex characters, got %d", len(parts[2]))
+	}
+
+	for i := range u.rand {
+		b, err := strconv.ParseUint(parts[2][2*i:2*i+2], 16, 8)
+		if err != nil {
+			return fmt.Errorf("could not parse random part at index %d: %v", 2*i, err)
+		}
+		u.rand[i] = byte(b)
+	}
+
+	return nil
+}
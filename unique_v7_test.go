@@ -0,0 +1,75 @@
+package go_unique_ts
+
+import (
+	"testing"
+)
+
+func TestV7Order(t *testing.T) {
+	g := NewGenerator()
+
+	ms := nowMs()
+	t1 := g.NewAtV7(ms)
+	t2 := g.NewAtV7(ms + 1)
+
+	if t1.String() >= t2.String() {
+		t.Fatalf("timestamps are not ordered correctly.  t1=%s, t2=%s", t1, t2)
+	}
+}
+
+func TestV7SameMillisecond(t *testing.T) {
+	g := NewGenerator()
+
+	ms := nowMs()
+	t1 := g.NewAtV7(ms)
+	t2 := g.NewAtV7(ms)
+
+	if t2.String() <= t1.String() {
+		t.Fatalf("repeated millisecond did not produce an increasing string: t1=%s, t2=%s", t1, t2)
+	}
+}
+
+func TestV7CounterOverflow(t *testing.T) {
+	g := NewGenerator()
+
+	ms := nowMs()
+	t1 := g.NewAtV7(ms)
+	for i := 0; i < 256; i++ {
+		t1 = g.NewAtV7(ms)
+	}
+
+	if t1.TimestampMs <= ms {
+		t.Fatalf("counter overflow should have rolled the millisecond forward, got %d", t1.TimestampMs)
+	}
+}
+
+func TestV7Parse(t *testing.T) {
+	g := NewGenerator()
+
+	t1 := g.NewV7()
+	t2 := UniqueTimestampV7{}
+
+	t.Log(t1.String())
+
+	err := t2.FromString(t1.String())
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	if t2 != t1 {
+		t.Fatalf("round trip through FromString did not preserve the value: %s != %s", t2, t1)
+	}
+}
+
+func TestV7ParseMalformed(t *testing.T) {
+	var u UniqueTimestampV7
+
+	for _, val := range []string{
+		"",
+		"019fafcab8b1",
+		"019fafcab8b1-00-0fdf13f3b2",
+	} {
+		if err := u.FromString(val); err == nil {
+			t.Fatalf("expected an error parsing %q, got none", val)
+		}
+	}
+}